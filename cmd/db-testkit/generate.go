@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/daana-code/db-testkit/pkg/config"
+	"github.com/daana-code/db-testkit/pkg/docker"
+	"github.com/daana-code/db-testkit/pkg/generator"
+)
+
+// runGenerate implements the `db-testkit generate --platform <platform>`
+// subcommand, regenerating CI config from docker-compose.yml so local dev
+// and CI credentials stay in lockstep.
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	composePath := fs.String("compose", "docker-compose.yml", "path to docker-compose.yml")
+	platform := fs.String("platform", "all", "which CI config to generate: gitlab, github, drone, or all")
+	outputDir := fs.String("output-dir", ".", "directory to write generated CI config into")
+	overlayPath := fs.String("overlay", "db-testkit.yaml", "path to db-testkit.yaml overlay file (optional)")
+	env := fs.String("env", "ci", "environment to apply from the overlay file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	compose, err := docker.ParseDockerCompose(*composePath)
+	if err != nil {
+		return err
+	}
+	creds, err := docker.ExtractCredentials(compose)
+	if err != nil {
+		return err
+	}
+
+	var overlay *config.Overlay
+	if _, statErr := os.Stat(*overlayPath); statErr == nil {
+		overlay, err = config.LoadOverlay(*overlayPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	generators := map[string]func(*docker.TestDBCredentials, string) error{
+		"gitlab": func(c *docker.TestDBCredentials, dir string) error {
+			return generator.GenerateGitLabCI(c, dir+"/.gitlab-ci.yml", overlay, *env)
+		},
+		"github": func(c *docker.TestDBCredentials, dir string) error {
+			return generator.GenerateGitHubActions(c, dir+"/.github/workflows/test.yml", overlay, *env)
+		},
+		"drone": func(c *docker.TestDBCredentials, dir string) error {
+			return generator.GenerateDroneCI(c, dir+"/.drone.yml", overlay, *env)
+		},
+	}
+
+	if *platform == "all" {
+		for name, gen := range generators {
+			if err := gen(creds, *outputDir); err != nil {
+				return fmt.Errorf("failed to generate %s CI config: %w", name, err)
+			}
+		}
+		fmt.Println("✅ generated CI config for gitlab, github, drone")
+		return nil
+	}
+
+	gen, ok := generators[*platform]
+	if !ok {
+		return fmt.Errorf("unknown platform %q: must be one of gitlab, github, drone, all", *platform)
+	}
+	if err := gen(creds, *outputDir); err != nil {
+		return fmt.Errorf("failed to generate %s CI config: %w", *platform, err)
+	}
+	fmt.Printf("✅ generated CI config for %s\n", *platform)
+	return nil
+}
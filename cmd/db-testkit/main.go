@@ -0,0 +1,33 @@
+// Command db-testkit is the CLI entry point for db-testkit's generated
+// Taskfile targets.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: db-testkit <command> [flags]")
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "migrate":
+		err = runMigrate(os.Args[2:])
+	case "generate":
+		err = runGenerate(os.Args[2:])
+	case "schema":
+		err = runSchema(os.Args[2:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
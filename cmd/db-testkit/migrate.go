@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/lib/pq"
+
+	"github.com/daana-code/db-testkit/pkg/migrator"
+)
+
+// runMigrate implements the `db-testkit migrate up|down|status` subcommand.
+func runMigrate(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: db-testkit migrate <up|down|status> [flags]")
+	}
+
+	fs := flag.NewFlagSet("migrate "+args[0], flag.ExitOnError)
+	dir := fs.String("dir", "migrations", "directory containing NNNN_name.up.sql / .down.sql files")
+	dsn := fs.String("dsn", os.Getenv("DATABASE_URL"), "postgres connection string (defaults to $DATABASE_URL)")
+	steps := fs.Int("steps", 1, "number of migrations to roll back (down only)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *dsn == "" {
+		return fmt.Errorf("no DSN provided: pass -dsn or set DATABASE_URL")
+	}
+
+	db, err := sql.Open("postgres", *dsn)
+	if err != nil {
+		return fmt.Errorf("failed to open database connection: %w", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		if err := migrator.Up(ctx, db, *dir); err != nil {
+			return err
+		}
+		fmt.Println("✅ migrations applied")
+	case "down":
+		if err := migrator.Down(ctx, db, *dir, *steps); err != nil {
+			return err
+		}
+		fmt.Printf("✅ rolled back up to %d migration(s)\n", *steps)
+	case "status":
+		entries, err := migrator.Status(ctx, db, *dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			fmt.Println(entry.String())
+		}
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q", args[0])
+	}
+
+	return nil
+}
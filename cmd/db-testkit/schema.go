@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/lib/pq"
+
+	"github.com/daana-code/db-testkit/pkg/schemaiso"
+)
+
+// runSchema implements the `db-testkit schema <reset>` subcommand.
+func runSchema(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: db-testkit schema <reset> [flags]")
+	}
+
+	fs := flag.NewFlagSet("schema "+args[0], flag.ExitOnError)
+	dsn := fs.String("dsn", os.Getenv("DATABASE_URL"), "postgres connection string (defaults to $DATABASE_URL)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *dsn == "" {
+		return fmt.Errorf("no DSN provided: pass -dsn or set DATABASE_URL")
+	}
+
+	db, err := sql.Open("postgres", *dsn)
+	if err != nil {
+		return fmt.Errorf("failed to open database connection: %w", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "reset":
+		if err := schemaiso.Reset(ctx, db); err != nil {
+			return err
+		}
+		fmt.Println("✅ schemas reset")
+	default:
+		return fmt.Errorf("unknown schema subcommand %q", args[0])
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+// Package randsuffix generates short random identifiers used to keep
+// schema names unique across concurrent test runs.
+package randsuffix
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// New returns an 8-character hex string suitable for appending to a name.
+func New() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
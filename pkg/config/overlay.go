@@ -0,0 +1,93 @@
+// Package config loads environment-scoped overrides for the credentials
+// extracted from docker-compose.yml, so the same compose file can produce
+// different generated artifacts for local, ci, and staging without being
+// edited per environment.
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/daana-code/db-testkit/pkg/docker"
+)
+
+// EnvOverride patches individual fields of docker.TestDBCredentials for a
+// single environment. Fields are pointers so that an absent key in
+// db-testkit.yaml leaves the corresponding base value untouched, rather
+// than clobbering it with a zero value.
+type EnvOverride struct {
+	CustomerHost     *string `yaml:"customer_host"`
+	CustomerPort     *string `yaml:"customer_port"`
+	CustomerUser     *string `yaml:"customer_user"`
+	CustomerPassword *string `yaml:"customer_password"`
+	CustomerDB       *string `yaml:"customer_db"`
+	InternalHost     *string `yaml:"internal_host"`
+	InternalPort     *string `yaml:"internal_port"`
+	InternalUser     *string `yaml:"internal_user"`
+	InternalPassword *string `yaml:"internal_password"`
+	InternalDB       *string `yaml:"internal_db"`
+}
+
+// Overlay is the parsed form of db-testkit.yaml: a set of per-environment
+// patches keyed by environment name (e.g. "local", "ci", "staging").
+type Overlay struct {
+	Environments map[string]EnvOverride `yaml:"environments"`
+}
+
+// LoadOverlay reads and parses a db-testkit.yaml file describing
+// environment-scoped credential overrides.
+func LoadOverlay(path string) (*Overlay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var overlay Overlay
+	if err := yaml.Unmarshal(data, &overlay); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return &overlay, nil
+}
+
+// Apply deep-merges the override registered for env onto a copy of creds
+// and returns the result. Unset (nil) pointer fields in the override leave
+// the base value untouched. If env has no registered override, a copy of
+// creds is returned unchanged. o may be nil, in which case Apply is a no-op
+// that returns a copy of creds; callers use this to make the overlay
+// optional rather than special-casing a missing db-testkit.yaml themselves.
+func (o *Overlay) Apply(env string, creds *docker.TestDBCredentials) (*docker.TestDBCredentials, error) {
+	merged := *creds
+
+	if o == nil {
+		return &merged, nil
+	}
+
+	override, ok := o.Environments[env]
+	if !ok {
+		return &merged, nil
+	}
+
+	applyString(&merged.CustomerHost, override.CustomerHost)
+	applyString(&merged.CustomerPort, override.CustomerPort)
+	applyString(&merged.CustomerUser, override.CustomerUser)
+	applyString(&merged.CustomerPassword, override.CustomerPassword)
+	applyString(&merged.CustomerDB, override.CustomerDB)
+	applyString(&merged.InternalHost, override.InternalHost)
+	applyString(&merged.InternalPort, override.InternalPort)
+	applyString(&merged.InternalUser, override.InternalUser)
+	applyString(&merged.InternalPassword, override.InternalPassword)
+	applyString(&merged.InternalDB, override.InternalDB)
+
+	return &merged, nil
+}
+
+// applyString overwrites *dst with *override, leaving dst untouched when
+// override is nil.
+func applyString(dst *string, override *string) {
+	if override != nil {
+		*dst = *override
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/daana-code/db-testkit/pkg/docker"
+)
+
+func baseCreds() *docker.TestDBCredentials {
+	return &docker.TestDBCredentials{
+		CustomerHost:     "localhost",
+		CustomerPort:     "5555",
+		CustomerUser:     "customer",
+		CustomerPassword: "customer-pass",
+		CustomerDB:       "customerdb",
+		InternalHost:     "localhost",
+		InternalPort:     "6666",
+		InternalUser:     "internal",
+		InternalPassword: "internal-pass",
+		InternalDB:       "internaldb",
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestOverlayApplyNilOverlayReturnsCredsUnchanged(t *testing.T) {
+	var overlay *Overlay
+	creds := baseCreds()
+
+	merged, err := overlay.Apply("ci", creds)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if *merged != *creds {
+		t.Errorf("merged = %+v, want unchanged %+v", merged, creds)
+	}
+}
+
+func TestOverlayApplyUnknownEnvReturnsCredsUnchanged(t *testing.T) {
+	overlay := &Overlay{Environments: map[string]EnvOverride{
+		"ci": {CustomerHost: strPtr("postgres")},
+	}}
+	creds := baseCreds()
+
+	merged, err := overlay.Apply("staging", creds)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if *merged != *creds {
+		t.Errorf("merged = %+v, want unchanged %+v", merged, creds)
+	}
+}
+
+func TestOverlayApplyOverridesOnlySetFields(t *testing.T) {
+	overlay := &Overlay{Environments: map[string]EnvOverride{
+		"ci": {
+			CustomerHost: strPtr("postgres"),
+			CustomerPort: strPtr("5432"),
+		},
+	}}
+	creds := baseCreds()
+
+	merged, err := overlay.Apply("ci", creds)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if merged.CustomerHost != "postgres" {
+		t.Errorf("CustomerHost = %q, want %q", merged.CustomerHost, "postgres")
+	}
+	if merged.CustomerPort != "5432" {
+		t.Errorf("CustomerPort = %q, want %q", merged.CustomerPort, "5432")
+	}
+	// Fields with no override in the patch must be left untouched, not
+	// clobbered with a zero value.
+	if merged.CustomerUser != creds.CustomerUser {
+		t.Errorf("CustomerUser = %q, want unchanged %q", merged.CustomerUser, creds.CustomerUser)
+	}
+	if merged.InternalHost != creds.InternalHost {
+		t.Errorf("InternalHost = %q, want unchanged %q", merged.InternalHost, creds.InternalHost)
+	}
+}
+
+func TestOverlayApplyDoesNotMutateInput(t *testing.T) {
+	overlay := &Overlay{Environments: map[string]EnvOverride{
+		"ci": {CustomerHost: strPtr("postgres")},
+	}}
+	creds := baseCreds()
+	original := *creds
+
+	if _, err := overlay.Apply("ci", creds); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if *creds != original {
+		t.Errorf("Apply mutated its input creds: got %+v, want %+v", *creds, original)
+	}
+}
@@ -0,0 +1,306 @@
+// Package dbcontainer provisions disposable PostgreSQL containers for test
+// runs using the Docker Engine SDK, as an alternative to the static
+// docker-compose-based flow in pkg/docker.
+package dbcontainer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+
+	"github.com/daana-code/db-testkit/pkg/docker"
+)
+
+// Spec describes the container to provision.
+type Spec struct {
+	Image         string // defaults to "postgres" if empty
+	Version       string // defaults to "15-alpine" if empty
+	User          string
+	Password      string
+	DB            string
+	Port          string        // container port to expose, defaults to "5432"
+	Volumes       []string      // additional bind/volume mounts, "source:target" form
+	HealthTimeout time.Duration // defaults to 30s if zero
+}
+
+// RunningDB is a disposable PostgreSQL container started by Start.
+type RunningDB struct {
+	ContainerID   string
+	ContainerName string
+	Host          string
+	Port          string
+	User          string
+	Password      string
+	DB            string
+
+	cli        *client.Client
+	volumeName string
+}
+
+const (
+	defaultImage         = "postgres"
+	defaultVersion       = "15-alpine"
+	defaultContainerPort = "5432"
+	defaultHealthTimeout = 30 * time.Second
+)
+
+// Start pulls the requested Postgres image if necessary, creates and starts
+// a container bound to a random free host port, and blocks until pg_isready
+// reports healthy or spec.HealthTimeout elapses. On any error, including a
+// health-check timeout, the returned *RunningDB is nil and any container or
+// volume already created has been torn down, so callers can rely on the
+// usual `db, err := Start(...); if err != nil { return err }` idiom without
+// leaking resources. As a backstop against a caller that never calls Stop
+// or Cleanup on a successfully started RunningDB, Start also registers a
+// finalizer that removes its container and volume once it is collected.
+func Start(ctx context.Context, spec Spec) (*RunningDB, error) {
+	img := spec.Image
+	if img == "" {
+		img = defaultImage
+	}
+	version := spec.Version
+	if version == "" {
+		version = defaultVersion
+	}
+	ref := fmt.Sprintf("%s:%s", img, version)
+
+	containerPort := spec.Port
+	if containerPort == "" {
+		containerPort = defaultContainerPort
+	}
+
+	healthTimeout := spec.HealthTimeout
+	if healthTimeout == 0 {
+		healthTimeout = defaultHealthTimeout
+	}
+
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %w", err)
+	}
+
+	if err := ensureImage(ctx, cli, ref); err != nil {
+		return nil, err
+	}
+
+	suffix, err := randomSuffix()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate container name suffix: %w", err)
+	}
+	name := fmt.Sprintf("db-testkit-%s", suffix)
+	volumeName := fmt.Sprintf("db-testkit-vol-%s", suffix)
+
+	natPort, err := nat.NewPort("tcp", containerPort)
+	if err != nil {
+		return nil, fmt.Errorf("invalid container port %q: %w", containerPort, err)
+	}
+
+	binds := make([]string, 0, len(spec.Volumes)+1)
+	binds = append(binds, fmt.Sprintf("%s:/var/lib/postgresql/data", volumeName))
+	binds = append(binds, spec.Volumes...)
+
+	containerCfg := &container.Config{
+		Image: ref,
+		Env: []string{
+			"POSTGRES_USER=" + spec.User,
+			"POSTGRES_PASSWORD=" + spec.Password,
+			"POSTGRES_DB=" + spec.DB,
+		},
+		ExposedPorts: nat.PortSet{natPort: struct{}{}},
+	}
+	hostCfg := &container.HostConfig{
+		Binds: binds,
+		PortBindings: nat.PortMap{
+			natPort: []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "0"}},
+		},
+		AutoRemove: false,
+	}
+
+	created, err := cli.ContainerCreate(ctx, containerCfg, hostCfg, &network.NetworkingConfig{}, nil, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container %s: %w", name, err)
+	}
+
+	// removeCreated tears down the container and its volume on the error
+	// paths below, before a *RunningDB exists to call Cleanup on.
+	removeCreated := func() {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = cli.ContainerRemove(cleanupCtx, created.ID, container.RemoveOptions{Force: true, RemoveVolumes: true})
+		_ = cli.VolumeRemove(cleanupCtx, volumeName, true)
+	}
+
+	if err := cli.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+		removeCreated()
+		return nil, fmt.Errorf("failed to start container %s: %w", name, err)
+	}
+
+	inspect, err := cli.ContainerInspect(ctx, created.ID)
+	if err != nil {
+		removeCreated()
+		return nil, fmt.Errorf("failed to inspect container %s: %w", name, err)
+	}
+	bindings, ok := inspect.NetworkSettings.Ports[natPort]
+	if !ok || len(bindings) == 0 {
+		removeCreated()
+		return nil, fmt.Errorf("container %s did not publish port %s", name, containerPort)
+	}
+	hostPort := bindings[0].HostPort
+
+	rdb := &RunningDB{
+		ContainerID:   created.ID,
+		ContainerName: name,
+		Host:          "localhost",
+		Port:          hostPort,
+		User:          spec.User,
+		Password:      spec.Password,
+		DB:            spec.DB,
+		cli:           cli,
+		volumeName:    volumeName,
+	}
+
+	if err := waitHealthy(ctx, cli, created.ID, spec.User, spec.DB, healthTimeout); err != nil {
+		// The container never became healthy, so there is nothing usable to
+		// hand back. Clean it up here rather than returning a non-nil
+		// *RunningDB alongside the error: callers follow the normal Go idiom
+		// of `db, err := Start(...); if err != nil { return err }` and would
+		// otherwise never call Cleanup, leaking the container and volume.
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = rdb.Cleanup(cleanupCtx)
+		return nil, err
+	}
+
+	// Belt-and-braces: if a caller forgets to call Cleanup (or Stop), still
+	// reclaim the container and volume once rdb is garbage collected.
+	runtime.SetFinalizer(rdb, func(r *RunningDB) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = r.Cleanup(ctx)
+	})
+
+	return rdb, nil
+}
+
+// ensureImage pulls ref unless it is already present locally.
+func ensureImage(ctx context.Context, cli *client.Client, ref string) error {
+	if _, _, err := cli.ImageInspectWithRaw(ctx, ref); err == nil {
+		return nil
+	}
+
+	reader, err := cli.ImagePull(ctx, ref, image.PullOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", ref, err)
+	}
+	defer reader.Close()
+
+	// Drain the pull progress stream; we don't surface it.
+	buf := make([]byte, 4096)
+	for {
+		if _, err := reader.Read(buf); err != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// waitHealthy polls `pg_isready` inside the container until it succeeds, the
+// context is canceled, or timeout elapses.
+func waitHealthy(ctx context.Context, cli *client.Client, containerID, user, db string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		execID, err := cli.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+			Cmd:          []string{"pg_isready", "-U", user, "-d", db},
+			AttachStdout: true,
+			AttachStderr: true,
+		})
+		if err == nil {
+			attach, err := cli.ContainerExecAttach(ctx, execID.ID, container.ExecStartOptions{})
+			if err == nil {
+				// Wait for pg_isready to finish before inspecting its exit
+				// code; a still-running exec reports ExitCode 0.
+				_, _ = io.Copy(io.Discard, attach.Reader)
+				attach.Close()
+				inspect, err := cli.ContainerExecInspect(ctx, execID.ID)
+				if err == nil && !inspect.Running && inspect.ExitCode == 0 {
+					return nil
+				}
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("container %s did not become healthy within %s", containerID, timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("container %s: waiting for healthy state: %w", containerID, ctx.Err())
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// Stop stops the running container without removing it or its volume.
+func (r *RunningDB) Stop(ctx context.Context) error {
+	timeout := 10
+	if err := r.cli.ContainerStop(ctx, r.ContainerID, container.StopOptions{Timeout: &timeout}); err != nil {
+		return fmt.Errorf("failed to stop container %s: %w", r.ContainerName, err)
+	}
+	return nil
+}
+
+// Cleanup stops the container, then removes the container and its volume.
+// It is safe to call even if Stop has already been called, and clears the
+// finalizer registered by Start so a later GC doesn't redundantly retry it.
+func (r *RunningDB) Cleanup(ctx context.Context) error {
+	runtime.SetFinalizer(r, nil)
+
+	if err := r.cli.ContainerRemove(ctx, r.ContainerID, container.RemoveOptions{Force: true, RemoveVolumes: true}); err != nil {
+		return fmt.Errorf("failed to remove container %s: %w", r.ContainerName, err)
+	}
+	if err := r.cli.VolumeRemove(ctx, r.volumeName, true); err != nil {
+		return fmt.Errorf("failed to remove volume %s: %w", r.volumeName, err)
+	}
+	return nil
+}
+
+// ExtractCredentials combines two RunningDB instances, one standing in for
+// the customer database and one for the internal database, into a
+// *docker.TestDBCredentials. This lets callers provision databases via the
+// SDK while feeding the same struct that pkg/generator already consumes.
+func ExtractCredentials(customer, internal *RunningDB) *docker.TestDBCredentials {
+	return &docker.TestDBCredentials{
+		CustomerHost:     customer.Host,
+		CustomerPort:     customer.Port,
+		CustomerUser:     customer.User,
+		CustomerPassword: customer.Password,
+		CustomerDB:       customer.DB,
+		InternalHost:     internal.Host,
+		InternalPort:     internal.Port,
+		InternalUser:     internal.User,
+		InternalPassword: internal.Password,
+		InternalDB:       internal.DB,
+	}
+}
+
+// randomSuffix returns an 8-character hex string used to keep container and
+// volume names unique across concurrent test runs.
+func randomSuffix() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -25,6 +25,10 @@ func ParseDockerCompose(path string) (*DockerCompose, error) {
 // ExtractCredentials extracts database credentials from a parsed docker-compose configuration.
 // It looks for db-test-customer and db-test-internal services and extracts their PostgreSQL
 // environment variables and port mappings.
+//
+// ExtractCredentials is a thin backward-compatible wrapper around
+// ExtractServices for the two well-known service names; new code targeting
+// other topologies should call ExtractServices directly.
 func ExtractCredentials(dockerCompose *DockerCompose) (*TestDBCredentials, error) {
 	customerService, ok := dockerCompose.Services["db-test-customer"]
 	if !ok {
@@ -36,33 +40,35 @@ func ExtractCredentials(dockerCompose *DockerCompose) (*TestDBCredentials, error
 		return nil, fmt.Errorf("db-test-internal service not found in docker-compose.yml")
 	}
 
-	// Extract customer credentials
-	customerUser := customerService.Environment["POSTGRES_USER"]
-	customerPassword := customerService.Environment["POSTGRES_PASSWORD"]
-	customerDB := customerService.Environment["POSTGRES_DB"]
-
-	// Extract port from port mapping (e.g., "5555:5432" -> "5555")
-	customerPort := extractHostPort(customerService.Ports, "5555")
-
-	// Extract internal credentials
-	internalUser := internalService.Environment["POSTGRES_USER"]
-	internalPassword := internalService.Environment["POSTGRES_PASSWORD"]
-	internalDB := internalService.Environment["POSTGRES_DB"]
+	services, err := ExtractServices(dockerCompose, NameList{"db-test-customer", "db-test-internal"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract db-test-customer/db-test-internal credentials: %w", err)
+	}
+	customer := services["db-test-customer"]
+	internal := services["db-test-internal"]
 
-	// Extract port from port mapping (e.g., "6666:5432" -> "6666")
-	internalPort := extractHostPort(internalService.Ports, "6666")
+	// Fall back to the documented defaults when no port mapping is present,
+	// matching the pre-ExtractServices behavior.
+	customerPort := customer.Port
+	if customerPort == "" {
+		customerPort = extractHostPort(customerService.Ports, "5555")
+	}
+	internalPort := internal.Port
+	if internalPort == "" {
+		internalPort = extractHostPort(internalService.Ports, "6666")
+	}
 
 	return &TestDBCredentials{
-		CustomerHost:     "localhost",
+		CustomerHost:     customer.Host,
 		CustomerPort:     customerPort,
-		CustomerUser:     customerUser,
-		CustomerPassword: customerPassword,
-		CustomerDB:       customerDB,
-		InternalHost:     "localhost",
+		CustomerUser:     customer.User,
+		CustomerPassword: customer.Password,
+		CustomerDB:       customer.DB,
+		InternalHost:     internal.Host,
 		InternalPort:     internalPort,
-		InternalUser:     internalUser,
-		InternalPassword: internalPassword,
-		InternalDB:       internalDB,
+		InternalUser:     internal.User,
+		InternalPassword: internal.Password,
+		InternalDB:       internal.DB,
 	}, nil
 }
 
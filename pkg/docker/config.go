@@ -9,8 +9,10 @@ type DockerCompose struct {
 
 // Service represents a single service definition in docker-compose.yml
 type Service struct {
+	Image       string            `yaml:"image"`
 	Environment map[string]string `yaml:"environment"`
 	Ports       []string          `yaml:"ports"`
+	Labels      map[string]string `yaml:"labels"`
 }
 
 // TestDBCredentials holds extracted credentials from docker-compose.yml
@@ -0,0 +1,142 @@
+package docker
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Engine identifies the database engine a compose service is running.
+type Engine string
+
+const (
+	EnginePostgres Engine = "postgres"
+	EngineMySQL    Engine = "mysql"
+	EngineMariaDB  Engine = "mariadb"
+	EngineUnknown  Engine = "unknown"
+)
+
+// roleLabel is the docker-compose label used to tag a service's role
+// (e.g. "customer", "internal"), analogous to how POSTGRES_* env vars tag
+// credentials.
+const roleLabel = "db-testkit.role"
+
+// ServiceCredentials holds everything db-testkit could extract about a
+// single compose service: its role, engine, connection details, and any
+// environment variables that didn't map to a known credential field.
+type ServiceCredentials struct {
+	Name     string
+	Role     string
+	Engine   Engine
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DB       string
+	Extra    map[string]string
+}
+
+// ServiceMatcher decides whether a named compose service should be
+// extracted by ExtractServices.
+type ServiceMatcher interface {
+	Matches(name string, svc Service) bool
+}
+
+// NameList matches services whose name appears in the list verbatim.
+type NameList []string
+
+// Matches reports whether name is present in the list.
+func (n NameList) Matches(name string, _ Service) bool {
+	for _, candidate := range n {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Glob matches services whose name satisfies a shell glob pattern, e.g.
+// "db-test-*".
+type Glob string
+
+// Matches reports whether name satisfies the glob pattern.
+func (g Glob) Matches(name string, _ Service) bool {
+	ok, _ := path.Match(string(g), name)
+	return ok
+}
+
+// Predicate matches services based on an arbitrary function over the
+// service's name, labels, and environment.
+type Predicate func(name string, svc Service) bool
+
+// Matches invokes the predicate.
+func (p Predicate) Matches(name string, svc Service) bool {
+	return p(name, svc)
+}
+
+// ExtractServices walks every service in compose and returns the subset
+// accepted by matcher as ServiceCredentials, keyed by service name.
+func ExtractServices(compose *DockerCompose, matcher ServiceMatcher) (map[string]*ServiceCredentials, error) {
+	services := make(map[string]*ServiceCredentials)
+
+	for name, svc := range compose.Services {
+		if !matcher.Matches(name, svc) {
+			continue
+		}
+
+		user, hasUser := svc.Environment["POSTGRES_USER"]
+		password, hasPassword := svc.Environment["POSTGRES_PASSWORD"]
+		db, hasDB := svc.Environment["POSTGRES_DB"]
+		if !hasUser && !hasPassword && !hasDB {
+			// Fall back to MySQL/MariaDB-style env vars.
+			user = svc.Environment["MYSQL_USER"]
+			password = svc.Environment["MYSQL_PASSWORD"]
+			db = svc.Environment["MYSQL_DATABASE"]
+		}
+
+		extra := make(map[string]string)
+		for k, v := range svc.Environment {
+			switch k {
+			case "POSTGRES_USER", "POSTGRES_PASSWORD", "POSTGRES_DB",
+				"MYSQL_USER", "MYSQL_PASSWORD", "MYSQL_DATABASE":
+				continue
+			default:
+				extra[k] = v
+			}
+		}
+
+		services[name] = &ServiceCredentials{
+			Name:     name,
+			Role:     svc.Labels[roleLabel],
+			Engine:   detectEngine(svc.Image),
+			Host:     "localhost",
+			Port:     extractHostPort(svc.Ports, ""),
+			User:     user,
+			Password: password,
+			DB:       db,
+			Extra:    extra,
+		}
+	}
+
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no services matched")
+	}
+
+	return services, nil
+}
+
+// detectEngine guesses the database engine from a compose image reference,
+// e.g. "postgres:15-alpine" -> EnginePostgres.
+func detectEngine(image string) Engine {
+	ref := strings.ToLower(image)
+	switch {
+	case strings.Contains(ref, "postgres"):
+		return EnginePostgres
+	case strings.Contains(ref, "mariadb"):
+		return EngineMariaDB
+	case strings.Contains(ref, "mysql"):
+		return EngineMySQL
+	default:
+		return EngineUnknown
+	}
+}
@@ -0,0 +1,111 @@
+package docker
+
+import "testing"
+
+func TestNameListMatches(t *testing.T) {
+	matcher := NameList{"db-test-customer", "db-test-internal"}
+
+	if !matcher.Matches("db-test-customer", Service{}) {
+		t.Error("expected NameList to match a listed name")
+	}
+	if matcher.Matches("other", Service{}) {
+		t.Error("expected NameList not to match an unlisted name")
+	}
+}
+
+func TestGlobMatches(t *testing.T) {
+	matcher := Glob("db-test-*")
+
+	if !matcher.Matches("db-test-customer", Service{}) {
+		t.Error("expected Glob to match a name satisfying the pattern")
+	}
+	if matcher.Matches("other-service", Service{}) {
+		t.Error("expected Glob not to match a name outside the pattern")
+	}
+}
+
+func TestPredicateMatches(t *testing.T) {
+	matcher := Predicate(func(name string, svc Service) bool {
+		_, ok := svc.Environment["POSTGRES_USER"]
+		return ok
+	})
+
+	if !matcher.Matches("any", Service{Environment: map[string]string{"POSTGRES_USER": "x"}}) {
+		t.Error("expected Predicate to match when POSTGRES_USER is present")
+	}
+	if matcher.Matches("any", Service{Environment: map[string]string{}}) {
+		t.Error("expected Predicate not to match when POSTGRES_USER is absent")
+	}
+}
+
+func TestDetectEngine(t *testing.T) {
+	tests := []struct {
+		image string
+		want  Engine
+	}{
+		{"postgres:15-alpine", EnginePostgres},
+		{"mariadb:11", EngineMariaDB},
+		{"mysql:8", EngineMySQL},
+		{"redis:7", EngineUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := detectEngine(tt.image); got != tt.want {
+			t.Errorf("detectEngine(%q) = %q, want %q", tt.image, got, tt.want)
+		}
+	}
+}
+
+func TestExtractServicesPostgresAndMySQLEnvVars(t *testing.T) {
+	compose := &DockerCompose{Services: map[string]Service{
+		"pg": {
+			Image: "postgres:15-alpine",
+			Environment: map[string]string{
+				"POSTGRES_USER":     "pguser",
+				"POSTGRES_PASSWORD": "pgpass",
+				"POSTGRES_DB":       "pgdb",
+			},
+			Ports:  []string{"5555:5432"},
+			Labels: map[string]string{roleLabel: "customer"},
+		},
+		"my": {
+			Image: "mysql:8",
+			Environment: map[string]string{
+				"MYSQL_USER":     "myuser",
+				"MYSQL_PASSWORD": "mypass",
+				"MYSQL_DATABASE": "mydb",
+			},
+		},
+	}}
+
+	services, err := ExtractServices(compose, NameList{"pg", "my"})
+	if err != nil {
+		t.Fatalf("ExtractServices: %v", err)
+	}
+
+	pg := services["pg"]
+	if pg.Engine != EnginePostgres || pg.User != "pguser" || pg.Password != "pgpass" || pg.DB != "pgdb" {
+		t.Errorf("pg service = %+v, want postgres credentials populated", pg)
+	}
+	if pg.Role != "customer" {
+		t.Errorf("pg.Role = %q, want %q", pg.Role, "customer")
+	}
+	if pg.Port != "5555" {
+		t.Errorf("pg.Port = %q, want %q", pg.Port, "5555")
+	}
+
+	my := services["my"]
+	if my.Engine != EngineMySQL || my.User != "myuser" || my.Password != "mypass" || my.DB != "mydb" {
+		t.Errorf("my service = %+v, want mysql credentials populated", my)
+	}
+}
+
+func TestExtractServicesNoMatchesErrors(t *testing.T) {
+	compose := &DockerCompose{Services: map[string]Service{
+		"pg": {Image: "postgres:15-alpine"},
+	}}
+
+	if _, err := ExtractServices(compose, NameList{"nonexistent"}); err == nil {
+		t.Fatal("expected an error when no services match, got nil")
+	}
+}
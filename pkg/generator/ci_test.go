@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/daana-code/db-testkit/pkg/docker"
+)
+
+func testCreds() *docker.TestDBCredentials {
+	return &docker.TestDBCredentials{
+		CustomerHost:     "localhost",
+		CustomerPort:     "5555",
+		CustomerUser:     "customer",
+		CustomerPassword: "customer-pass",
+		CustomerDB:       "customerdb",
+	}
+}
+
+var databaseURLPortRE = regexp.MustCompile(`DATABASE_URL.*@[\w.-]+:(\d+)/`)
+
+// connectionPort extracts the port embedded in the generated DATABASE_URL.
+func connectionPort(t *testing.T, content string) string {
+	t.Helper()
+	match := databaseURLPortRE.FindStringSubmatch(content)
+	if match == nil {
+		t.Fatalf("no DATABASE_URL found in generated config:\n%s", content)
+	}
+	return match[1]
+}
+
+func TestGenerateGitLabCIConnectsOnServicePort(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), ".gitlab-ci.yml")
+	if err := GenerateGitLabCI(testCreds(), outputPath, nil, ""); err != nil {
+		t.Fatalf("GenerateGitLabCI: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	if got := connectionPort(t, string(content)); got != postgresServicePort {
+		t.Errorf("DATABASE_URL port = %s, want %s (the port postgres listens on inside the GitLab services container, not CustomerPort=5555)", got, postgresServicePort)
+	}
+	if !regexp.MustCompile(`pg_isready -h postgres -p ` + postgresServicePort).MatchString(string(content)) {
+		t.Errorf("expected pg_isready to target port %s, got:\n%s", postgresServicePort, content)
+	}
+}
+
+func TestGenerateDroneCIConnectsOnServicePort(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), ".drone.yml")
+	if err := GenerateDroneCI(testCreds(), outputPath, nil, ""); err != nil {
+		t.Fatalf("GenerateDroneCI: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	if got := connectionPort(t, string(content)); got != postgresServicePort {
+		t.Errorf("DATABASE_URL port = %s, want %s (the port postgres listens on inside the Drone services container, not CustomerPort=5555)", got, postgresServicePort)
+	}
+	if !regexp.MustCompile(`pg_isready -h postgres -p ` + postgresServicePort).MatchString(string(content)) {
+		t.Errorf("expected pg_isready to target port %s, got:\n%s", postgresServicePort, content)
+	}
+}
+
+func TestGenerateGitHubActionsConnectsOnRepublishedHostPort(t *testing.T) {
+	creds := testCreds()
+	outputPath := filepath.Join(t.TempDir(), "test.yml")
+	if err := GenerateGitHubActions(creds, outputPath, nil, ""); err != nil {
+		t.Fatalf("GenerateGitHubActions: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	// GitHub Actions republishes the container's postgresServicePort onto
+	// CustomerPort on the job's own host network, so DATABASE_URL (dialing
+	// localhost) must use CustomerPort, not postgresServicePort.
+	if got := connectionPort(t, string(content)); got != creds.CustomerPort {
+		t.Errorf("DATABASE_URL port = %s, want %s (the republished host port)", got, creds.CustomerPort)
+	}
+	wantPortMapping := creds.CustomerPort + ":" + postgresServicePort
+	if !regexp.MustCompile(regexp.QuoteMeta(wantPortMapping)).MatchString(string(content)) {
+		t.Errorf("expected port mapping %q in generated config, got:\n%s", wantPortMapping, content)
+	}
+}
@@ -9,12 +9,20 @@ import (
 	"text/template"
 	"time"
 
-	"github.com/eikolytics/db-testkit/pkg/docker"
+	"github.com/daana-code/db-testkit/pkg/config"
+	"github.com/daana-code/db-testkit/pkg/docker"
 )
 
 // GenerateConnectionProfiles generates a connection-profiles-test.yaml file from database credentials.
 // The output file is created at the specified path, with parent directories created as needed.
-func GenerateConnectionProfiles(creds *docker.TestDBCredentials, outputPath string) error {
+// overlay and env are optional, per (*config.Overlay).Apply.
+func GenerateConnectionProfiles(creds *docker.TestDBCredentials, outputPath string, overlay *config.Overlay, env string) error {
+	merged, err := overlay.Apply(env, creds)
+	if err != nil {
+		return fmt.Errorf("failed to apply overlay for env %q: %w", env, err)
+	}
+	creds = merged
+
 	dir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
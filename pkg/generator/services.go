@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/daana-code/db-testkit/pkg/docker"
+)
+
+// serviceTaskEntry is a single service rendered into the per-service
+// Taskfile template, with a task-name-safe key derived from its role and
+// the client command appropriate for its engine.
+type serviceTaskEntry struct {
+	*docker.ServiceCredentials
+	TaskKey   string
+	ClientCmd string
+}
+
+// clientCmd returns the `docker exec` invocation that opens an interactive
+// client shell against svc, using the CLI native to its engine.
+func clientCmd(svc *docker.ServiceCredentials) string {
+	switch svc.Engine {
+	case docker.EngineMySQL, docker.EngineMariaDB:
+		return fmt.Sprintf("docker exec -it %s mysql -u%s -p%s %s", svc.Name, svc.User, svc.Password, svc.DB)
+	default:
+		return fmt.Sprintf("docker exec -it %s psql -U %s -d %s", svc.Name, svc.User, svc.DB)
+	}
+}
+
+// GenerateTaskfileServices generates a Taskfile.generated.yml with one
+// task block per discovered service, instead of the fixed
+// customer/internal template produced by GenerateTaskfile. It is meant for
+// topologies extracted via docker.ExtractServices rather than the
+// hardcoded db-test-customer/db-test-internal pair.
+func GenerateTaskfileServices(services map[string]*docker.ServiceCredentials, outputPath string) error {
+	entries := make([]serviceTaskEntry, 0, len(services))
+	for name, svc := range services {
+		key := svc.Role
+		if key == "" {
+			key = name
+		}
+		entries = append(entries, serviceTaskEntry{ServiceCredentials: svc, TaskKey: key, ClientCmd: clientCmd(svc)})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].TaskKey < entries[j].TaskKey })
+
+	tmpl := `# 🤖 THIS FILE IS AUTO-GENERATED from docker-compose.yml
+# DO NOT EDIT MANUALLY - Run 'go generate ./...' or your dev tool to regenerate
+# Generated on: {{.Timestamp}}
+# Source: docker-compose.yml (pluggable multi-service extraction)
+# ---------------------------------------------------------------------------
+
+version: '3'
+
+tasks:
+{{range .Services}}  test:db:start:{{.TaskKey}}:generated:
+    desc: Start the {{.TaskKey}} {{.Engine}} test database (using generated credentials)
+    cmds:
+      - echo "Starting {{.TaskKey}} {{.Engine}} test database ({{.Name}})..."
+      - docker compose up -d {{.Name}}
+
+  test:db:psql:{{.TaskKey}}:generated:
+    desc: Connect to the {{.TaskKey}} {{.Engine}} test database with its client (using generated credentials)
+    cmds:
+      - {{.ClientCmd}}
+
+{{end}}`
+
+	t, err := template.New("taskfile-services").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	data := struct {
+		Services  []serviceTaskEntry
+		Timestamp string
+	}{
+		Services:  entries,
+		Timestamp: time.Now().Format("2006-01-02 15:04:05 MST"),
+	}
+
+	return t.Execute(file, data)
+}
@@ -6,12 +6,20 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/daana-code/db-testkit/pkg/config"
 	"github.com/daana-code/db-testkit/pkg/docker"
 )
 
 // GenerateTaskfile generates a Taskfile.generated.yml with database tasks and credentials.
 // The output file is created at the specified path.
-func GenerateTaskfile(creds *docker.TestDBCredentials, outputPath string) error {
+// overlay and env are optional, per (*config.Overlay).Apply.
+func GenerateTaskfile(creds *docker.TestDBCredentials, outputPath string, overlay *config.Overlay, env string) error {
+	merged, err := overlay.Apply(env, creds)
+	if err != nil {
+		return fmt.Errorf("failed to apply overlay for env %q: %w", env, err)
+	}
+	creds = merged
+
 	tmpl := `# 🤖 THIS FILE IS AUTO-GENERATED from docker-compose.yml
 # DO NOT EDIT MANUALLY - Run 'go generate ./...' or your dev tool to regenerate
 # Generated on: {{.Timestamp}}
@@ -56,6 +64,33 @@ tasks:
     cmds:
       - docker exec -it pg-test-internal psql -U {{.InternalUser}} -d {{.InternalDB}}
 
+  test:db:reset:generated:
+    desc: Drop every schema except public and the system schemas in the test databases (schema-per-test cleanup, from db-testkit)
+    cmds:
+      - echo "Resetting schemas in automated testing customer database..."
+      - go run ./cmd/db-testkit schema reset -dsn "postgres://{{.CustomerUser}}:{{.CustomerPassword}}@{{.CustomerHost}}:{{.CustomerPort}}/{{.CustomerDB}}?sslmode=disable"
+      - echo "Resetting schemas in automated testing internal database..."
+      - go run ./cmd/db-testkit schema reset -dsn "postgres://{{.InternalUser}}:{{.InternalPassword}}@{{.InternalHost}}:{{.InternalPort}}/{{.InternalDB}}?sslmode=disable"
+      - echo "✅ Test database schemas reset!"
+
+  # Migration tasks (from db-testkit), backed by the db-testkit migrate CLI
+  db:migrate:up:generated:
+    desc: Apply pending migrations to the test customer database (from db-testkit)
+    cmds:
+      - echo "Applying migrations to test customer database..."
+      - go run ./cmd/db-testkit migrate up -dsn "postgres://{{.CustomerUser}}:{{.CustomerPassword}}@{{.CustomerHost}}:{{.CustomerPort}}/{{.CustomerDB}}?sslmode=disable" -dir migrations
+      - echo "✅ Migrations applied!"
+
+  db:migrate:down:generated:
+    desc: Roll back the most recently applied migration on the test customer database (from db-testkit)
+    cmds:
+      - go run ./cmd/db-testkit migrate down -dsn "postgres://{{.CustomerUser}}:{{.CustomerPassword}}@{{.CustomerHost}}:{{.CustomerPort}}/{{.CustomerDB}}?sslmode=disable" -dir migrations -steps 1
+
+  db:migrate:status:generated:
+    desc: Show applied/pending state of every migration against the test customer database (from db-testkit)
+    cmds:
+      - go run ./cmd/db-testkit migrate status -dsn "postgres://{{.CustomerUser}}:{{.CustomerPassword}}@{{.CustomerHost}}:{{.CustomerPort}}/{{.CustomerDB}}?sslmode=disable" -dir migrations
+
   # Seed data management tasks (from db-testkit)
   # Dev database seed tasks (manual testing)
   seed:load:dev:generated:
@@ -103,6 +138,8 @@ tasks:
   # Test database seed tasks (automated testing)
   seed:load:test:generated:
     desc: Load seed data into test customer database (configurable via SEED_DATA_PATH)
+    deps:
+      - db:migrate:up:generated
     cmds:
       - |
         SEED_FILE="${SEED_DATA_PATH:-../db-testkit/testdata/seeds/olist.sql}"
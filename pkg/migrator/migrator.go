@@ -0,0 +1,316 @@
+// Package migrator applies versioned, ordered SQL migrations to a Postgres
+// database and tracks which versions have been applied in a
+// schema_migrations table.
+package migrator
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Migration is a single versioned, ordered SQL migration discovered on disk.
+type Migration struct {
+	Version  int64
+	Name     string
+	UpPath   string
+	DownPath string
+	Checksum string
+}
+
+// AppliedMigration describes a row in schema_migrations.
+type AppliedMigration struct {
+	Version   int64
+	AppliedAt string
+	Checksum  string
+}
+
+var migrationFileRE = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+const createMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version     BIGINT PRIMARY KEY,
+	applied_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+	checksum    TEXT NOT NULL
+)`
+
+// Up applies every migration in dir whose version is not yet recorded in
+// schema_migrations, in ascending version order.
+func Up(ctx context.Context, db *sql.DB, dir string) error {
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureMigrationsTable(ctx, db); err != nil {
+		return err
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := applyMigration(ctx, db, m); err != nil {
+			return fmt.Errorf("failed to apply migration %d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Down rolls back the given number of applied migrations (or all of them,
+// if fewer are applied), most recent first, by running their .down.sql files.
+func Down(ctx context.Context, db *sql.DB, dir string, steps int) error {
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return err
+	}
+	byVersion := make(map[int64]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	if err := ensureMigrationsTable(ctx, db); err != nil {
+		return err
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+	versions := make([]int64, 0, len(applied))
+	for v := range applied {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+	if steps > len(versions) {
+		steps = len(versions)
+	}
+
+	for i := 0; i < steps; i++ {
+		version := versions[i]
+		m, ok := byVersion[version]
+		if !ok || m.DownPath == "" {
+			return fmt.Errorf("no down migration found for version %d", version)
+		}
+		if err := revertMigration(ctx, db, m); err != nil {
+			return fmt.Errorf("failed to revert migration %d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Status reports, for every migration found in dir, whether it has been
+// applied and whether its on-disk checksum still matches the recorded one.
+type StatusEntry struct {
+	Migration Migration
+	Applied   bool
+	Drifted   bool
+}
+
+// Status returns the applied/drift state of every migration in dir.
+func Status(ctx context.Context, db *sql.DB, dir string) ([]StatusEntry, error) {
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureMigrationsTable(ctx, db); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT version, checksum FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	appliedChecksums := make(map[int64]string)
+	for rows.Next() {
+		var version int64
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations row: %w", err)
+		}
+		appliedChecksums[version] = checksum
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate schema_migrations: %w", err)
+	}
+
+	entries := make([]StatusEntry, 0, len(migrations))
+	for _, m := range migrations {
+		checksum, applied := appliedChecksums[m.Version]
+		entries = append(entries, StatusEntry{
+			Migration: m,
+			Applied:   applied,
+			Drifted:   applied && checksum != m.Checksum,
+		})
+	}
+
+	return entries, nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, m Migration) error {
+	sqlBytes, err := os.ReadFile(m.UpPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", m.UpPath, err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, string(sqlBytes)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, checksum) VALUES ($1, $2)`,
+		m.Version, m.Checksum,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func revertMigration(ctx context.Context, db *sql.DB, m Migration) error {
+	sqlBytes, err := os.ReadFile(m.DownPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", m.DownPath, err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, string(sqlBytes)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func ensureMigrationsTable(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, createMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+func appliedVersions(ctx context.Context, db *sql.DB) (map[int64]struct{}, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]struct{})
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations row: %w", err)
+		}
+		applied[version] = struct{}{}
+	}
+	return applied, rows.Err()
+}
+
+// loadMigrations reads dir for NNNN_name.up.sql / NNNN_name.down.sql pairs
+// and returns them sorted by version.
+func loadMigrations(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory %s: %w", dir, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFileRE.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		name := match[2]
+		direction := match[3]
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		switch direction {
+		case "up":
+			m.UpPath = path
+			checksum, err := checksumFile(path)
+			if err != nil {
+				return nil, err
+			}
+			m.Checksum = checksum
+		case "down":
+			m.DownPath = path
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpPath == "" {
+			return nil, fmt.Errorf("migration %d_%s is missing an .up.sql file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+func checksumFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// String renders a StatusEntry as a single tab-separated line, e.g.
+// "0001_create_users\tapplied".
+func (e StatusEntry) String() string {
+	state := "pending"
+	switch {
+	case e.Drifted:
+		state = "applied (checksum drift)"
+	case e.Applied:
+		state = "applied"
+	}
+	return fmt.Sprintf("%04d_%s\t%s", e.Migration.Version, e.Migration.Name, state)
+}
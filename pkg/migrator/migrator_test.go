@@ -0,0 +1,126 @@
+package migrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMigration(t *testing.T, dir string, version int64, name, up, down string) {
+	t.Helper()
+	base := filepath.Join(dir, fmt.Sprintf("%04d_%s", version, name))
+	if err := os.WriteFile(base+".up.sql", []byte(up), 0o644); err != nil {
+		t.Fatalf("failed to write up migration: %v", err)
+	}
+	if down != "" {
+		if err := os.WriteFile(base+".down.sql", []byte(down), 0o644); err != nil {
+			t.Fatalf("failed to write down migration: %v", err)
+		}
+	}
+}
+
+func TestLoadMigrationsOrdersByVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeMigration(t, dir, 2, "add_index", "CREATE INDEX", "DROP INDEX")
+	writeMigration(t, dir, 1, "create_users", "CREATE TABLE users (id int)", "DROP TABLE users")
+
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(migrations))
+	}
+	if migrations[0].Version != 1 || migrations[0].Name != "create_users" {
+		t.Errorf("expected migration 1 first, got %+v", migrations[0])
+	}
+	if migrations[1].Version != 2 || migrations[1].Name != "add_index" {
+		t.Errorf("expected migration 2 second, got %+v", migrations[1])
+	}
+}
+
+func TestLoadMigrationsMissingUpFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "0001_create_users.down.sql"), []byte("DROP TABLE users"), 0o644); err != nil {
+		t.Fatalf("failed to write down migration: %v", err)
+	}
+
+	if _, err := loadMigrations(dir); err == nil {
+		t.Fatal("expected error for migration missing .up.sql file, got nil")
+	}
+}
+
+func TestLoadMigrationsChecksumMatchesFileContents(t *testing.T) {
+	dir := t.TempDir()
+	up := "CREATE TABLE users (id int)"
+	writeMigration(t, dir, 1, "create_users", up, "DROP TABLE users")
+
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte(up))
+	want := hex.EncodeToString(sum[:])
+	if migrations[0].Checksum != want {
+		t.Errorf("checksum = %s, want %s", migrations[0].Checksum, want)
+	}
+}
+
+func TestLoadMigrationsChecksumDriftOnEdit(t *testing.T) {
+	dir := t.TempDir()
+	writeMigration(t, dir, 1, "create_users", "CREATE TABLE users (id int)", "DROP TABLE users")
+
+	before, err := loadMigrations(dir)
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "0001_create_users.up.sql"), []byte("CREATE TABLE users (id bigint)"), 0o644); err != nil {
+		t.Fatalf("failed to rewrite up migration: %v", err)
+	}
+
+	after, err := loadMigrations(dir)
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+
+	if before[0].Checksum == after[0].Checksum {
+		t.Error("expected checksum to change after editing the up migration, but it stayed the same")
+	}
+}
+
+func TestStatusEntryString(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry StatusEntry
+		want  string
+	}{
+		{
+			name:  "pending",
+			entry: StatusEntry{Migration: Migration{Version: 1, Name: "create_users"}},
+			want:  "0001_create_users\tpending",
+		},
+		{
+			name:  "applied",
+			entry: StatusEntry{Migration: Migration{Version: 1, Name: "create_users"}, Applied: true},
+			want:  "0001_create_users\tapplied",
+		},
+		{
+			name:  "drifted",
+			entry: StatusEntry{Migration: Migration{Version: 1, Name: "create_users"}, Applied: true, Drifted: true},
+			want:  "0001_create_users\tapplied (checksum drift)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
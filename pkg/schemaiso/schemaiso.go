@@ -0,0 +1,133 @@
+// Package schemaiso gives Go tests isolation from one another by running
+// each test (or suite) inside its own uniquely-named Postgres schema,
+// instead of paying the cost of restarting containers between tests.
+package schemaiso
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/lib/pq"
+
+	"github.com/daana-code/db-testkit/internal/randsuffix"
+	"github.com/daana-code/db-testkit/pkg/docker"
+)
+
+// SeedFunc is run once inside the newly created schema, before Acquire
+// returns, to apply migrations or load fixture data.
+type SeedFunc func(ctx context.Context, db *sql.DB) error
+
+// Lease represents an acquired, isolated schema. DB is scoped to that
+// schema via search_path and can be used directly by test code.
+type Lease struct {
+	DB     *sql.DB
+	Schema string
+}
+
+// Acquire connects to the database described by creds, creates a uniquely
+// named schema, points search_path at it, and optionally runs seed inside
+// that schema. The returned Lease must be released with Release, typically
+// via defer, so the schema is dropped even if the calling test panics.
+func Acquire(ctx context.Context, creds *docker.TestDBCredentials, seed SeedFunc) (*Lease, error) {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		creds.CustomerHost, creds.CustomerPort, creds.CustomerUser, creds.CustomerPassword, creds.CustomerDB)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	suffix, err := randsuffix.New()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to generate schema suffix: %w", err)
+	}
+	schema := fmt.Sprintf("test_%s", suffix)
+
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE SCHEMA %s`, quoteIdent(schema))); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schema %s: %w", schema, err)
+	}
+
+	// search_path is passed as a connection startup parameter rather than
+	// via SET, so every connection in the pool, not only the one that ran
+	// the SET, resolves unqualified names in schema.
+	db.Close()
+	db, err = sql.Open("postgres", dsn+" search_path="+schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set search_path to %s: %w", schema, err)
+	}
+
+	if seed != nil {
+		if err := seed(ctx, db); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("seed callback failed for schema %s: %w", schema, err)
+		}
+	}
+
+	return &Lease{DB: db, Schema: schema}, nil
+}
+
+// Release drops the lease's schema (CASCADE) and closes its connection. It
+// is safe to call from a deferred recover, so the schema is cleaned up even
+// when the caller panics mid-test.
+func (l *Lease) Release(ctx context.Context) error {
+	defer l.DB.Close()
+
+	if _, err := l.DB.ExecContext(ctx, fmt.Sprintf(`DROP SCHEMA IF EXISTS %s CASCADE`, quoteIdent(l.Schema))); err != nil {
+		return fmt.Errorf("failed to drop schema %s: %w", l.Schema, err)
+	}
+	return nil
+}
+
+// Reset enumerates every schema in db other than public and the system
+// schemas (information_schema, pg_*) and drops it. It is
+// intended to run between test suites as a belt-and-braces sweep for leases
+// that were never released (e.g. after a crashed run). Reset operates on an
+// already-opened *sql.DB, mirroring pkg/migrator's Up/Down/Status, so the
+// db-testkit CLI can open the connection once and reuse it across commands.
+func Reset(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT schema_name FROM information_schema.schemata
+		WHERE schema_name NOT IN ('information_schema', 'public')
+		  AND schema_name NOT LIKE 'pg\_%' ESCAPE '\'
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to list schemas: %w", err)
+	}
+
+	var schemas []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan schema name: %w", err)
+		}
+		schemas = append(schemas, name)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate schemas: %w", err)
+	}
+	rows.Close()
+
+	for _, schema := range schemas {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf(`DROP SCHEMA IF EXISTS %s CASCADE`, quoteIdent(schema))); err != nil {
+			return fmt.Errorf("failed to drop schema %s: %w", schema, err)
+		}
+	}
+
+	return nil
+}
+
+// quoteIdent quotes name as a Postgres identifier, doubling any embedded
+// double quotes. Unlike fmt's %q (Go string-literal escaping), this
+// produces a valid SQL identifier even when name contains a literal `"`.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}